@@ -0,0 +1,256 @@
+package openstack
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/gophercloud/gophercloud"
+	gophercloudopenstack "github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+)
+
+// Config holds the authenticated gophercloud clients shared by every
+// resource in this provider. It is built once by
+// viettelidc.Provider()'s ConfigureContextFunc and cached for the lifetime
+// of a Terraform run; resources reach it via meta.(*Config).
+type Config struct {
+	AuthURL                     string
+	Username                    string
+	Password                    string
+	ApplicationCredentialID     string
+	ApplicationCredentialSecret string
+	ProjectID                   string
+	ProjectName                 string
+	DomainName                  string
+	Region                      string
+	Token                       string
+	Insecure                    bool
+	CACertFile                  string
+	ClientCertFile              string
+	ClientKeyFile               string
+
+	osClient *gophercloud.ProviderClient
+
+	computeClients    map[string]*gophercloud.ServiceClient
+	networkClients    map[string]*gophercloud.ServiceClient
+	dnsV2Clients      map[string]*gophercloud.ServiceClient
+	kubernetesClients map[string]*gophercloud.ServiceClient
+}
+
+// LoadAndValidate authenticates against auth_url and must be called once
+// before any of the *Client getters below.
+func (c *Config) LoadAndValidate() error {
+	ao := gophercloud.AuthOptions{
+		IdentityEndpoint:            c.AuthURL,
+		Username:                    c.Username,
+		Password:                    c.Password,
+		DomainName:                  c.DomainName,
+		TenantID:                    c.ProjectID,
+		TenantName:                  c.ProjectName,
+		TokenID:                     c.Token,
+		ApplicationCredentialID:     c.ApplicationCredentialID,
+		ApplicationCredentialSecret: c.ApplicationCredentialSecret,
+		AllowReauth:                 true,
+	}
+
+	client, err := gophercloudopenstack.NewClient(c.AuthURL)
+	if err != nil {
+		return fmt.Errorf("error creating Viettel IDC client: %s", err)
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	client.HTTPClient = http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+
+	if err := gophercloudopenstack.Authenticate(client, ao); err != nil {
+		return fmt.Errorf("error authenticating with Viettel IDC: %s", err)
+	}
+
+	c.osClient = client
+	c.computeClients = make(map[string]*gophercloud.ServiceClient)
+	c.networkClients = make(map[string]*gophercloud.ServiceClient)
+	c.dnsV2Clients = make(map[string]*gophercloud.ServiceClient)
+	c.kubernetesClients = make(map[string]*gophercloud.ServiceClient)
+
+	return nil
+}
+
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: c.Insecure}
+
+	if c.CACertFile != "" {
+		caCert, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cacert_file: %s", err)
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		config.RootCAs = caCertPool
+	}
+
+	if c.ClientCertFile != "" && c.ClientKeyFile != "" {
+		clientCert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client_cert/client_key: %s", err)
+		}
+		config.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return config, nil
+}
+
+// ComputeClient returns a cached compute ServiceClient for region, creating
+// one on first use.
+func (c *Config) ComputeClient(region string) (*gophercloud.ServiceClient, error) {
+	if client, ok := c.computeClients[region]; ok {
+		return client, nil
+	}
+
+	client, err := gophercloudopenstack.NewComputeV2(c.osClient, gophercloud.EndpointOpts{Region: region})
+	if err != nil {
+		return nil, err
+	}
+
+	c.computeClients[region] = client
+	return client, nil
+}
+
+// NetworkClient returns a cached networking ServiceClient for region,
+// creating one on first use.
+func (c *Config) NetworkClient(region string) (*gophercloud.ServiceClient, error) {
+	if client, ok := c.networkClients[region]; ok {
+		return client, nil
+	}
+
+	client, err := gophercloudopenstack.NewNetworkV2(c.osClient, gophercloud.EndpointOpts{Region: region})
+	if err != nil {
+		return nil, err
+	}
+
+	c.networkClients[region] = client
+	return client, nil
+}
+
+// DNSV2Client returns a cached DNS ServiceClient for region, creating one on
+// first use.
+func (c *Config) DNSV2Client(region string) (*gophercloud.ServiceClient, error) {
+	if client, ok := c.dnsV2Clients[region]; ok {
+		return client, nil
+	}
+
+	client, err := gophercloudopenstack.NewDNSV2(c.osClient, gophercloud.EndpointOpts{Region: region})
+	if err != nil {
+		return nil, err
+	}
+
+	c.dnsV2Clients[region] = client
+	return client, nil
+}
+
+// KubernetesClient returns a cached container-infra (Magnum) ServiceClient
+// for region, creating one on first use.
+func (c *Config) KubernetesClient(region string) (*gophercloud.ServiceClient, error) {
+	if client, ok := c.kubernetesClients[region]; ok {
+		return client, nil
+	}
+
+	client, err := gophercloudopenstack.NewContainerInfraV1(c.osClient, gophercloud.EndpointOpts{Region: region})
+	if err != nil {
+		return nil, err
+	}
+
+	c.kubernetesClients[region] = client
+	return client, nil
+}
+
+// GetRegion returns the region set on the resource, falling back to the
+// provider's configured region.
+func GetRegion(d *schema.ResourceData, config *Config) string {
+	if v, ok := d.GetOk("region"); ok {
+		return v.(string)
+	}
+	return config.Region
+}
+
+// CheckDeleted clears d's ID and swallows err when err represents a 404 from
+// the backend, so that Terraform treats the resource as already gone instead
+// of failing the read/delete. Any other error is wrapped with msg.
+func CheckDeleted(d *schema.ResourceData, err error, msg string) error {
+	if _, ok := err.(gophercloud.ErrDefault404); ok {
+		d.SetId("")
+		return nil
+	}
+
+	return fmt.Errorf("%s: %s", msg, err)
+}
+
+// MapValueSpecs collects the value_specs map set on d, used to pass through
+// provider-specific extensions to a CreateOpts' request body.
+func MapValueSpecs(d *schema.ResourceData) map[string]string {
+	return expandToMapStringString(d.Get("value_specs").(map[string]interface{}))
+}
+
+func expandToMapStringString(v map[string]interface{}) map[string]string {
+	m := make(map[string]string, len(v))
+	for key, val := range v {
+		if s, ok := val.(string); ok {
+			m[key] = s
+		}
+	}
+	return m
+}
+
+func expandToStringSlice(v []interface{}) []string {
+	s := make([]string, len(v))
+	for i, val := range v {
+		s[i] = val.(string)
+	}
+	return s
+}
+
+// dnsClientSetAuthHeader sets the X-Auth-Sudo-Project-ID header used by
+// Designate to act on behalf of project_id when it differs from the token's
+// scoped project.
+func dnsClientSetAuthHeader(d *schema.ResourceData, client *gophercloud.ServiceClient) error {
+	projectID := d.Get("project_id").(string)
+	if projectID == "" {
+		return nil
+	}
+
+	if client.MoreHeaders == nil {
+		client.MoreHeaders = map[string]string{}
+	}
+	client.MoreHeaders["X-Auth-Sudo-Project-Id"] = projectID
+	return nil
+}
+
+// ZoneCreateOpts wraps zones.CreateOpts to also send value_specs through to
+// the request body, mirroring the CreateOptsExt pattern gophercloud uses
+// elsewhere for provider extensions.
+type ZoneCreateOpts struct {
+	zones.CreateOpts
+	ValueSpecs map[string]string
+}
+
+// ToZoneCreateMap implements zones.CreateOptsBuilder.
+func (opts ZoneCreateOpts) ToZoneCreateMap() (map[string]interface{}, error) {
+	b, err := gophercloud.BuildRequestBody(opts.CreateOpts, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range opts.ValueSpecs {
+		b[k] = v
+	}
+
+	return b, nil
+}
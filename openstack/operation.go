@@ -0,0 +1,128 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+)
+
+// OperationWaiter is implemented by every long-running-operation poller that
+// goes through WaitForOperation. RefreshFunc is handed to
+// resource.StateChangeConf, Target and Pending describe the states that end
+// and continue the wait, and Error surfaces any terminal error the backend
+// reported once the wait is over. DNSZoneWaiter is the reference
+// implementation in this package; the compute instance and Kubernetes
+// cluster/node pool resources in package viettelidc implement this interface
+// with their own waiter types (computeInstanceWaiter, kubernetesClusterWaiter,
+// kubernetesNodePoolWaiter) to avoid an import cycle back into this package.
+type OperationWaiter interface {
+	RefreshFunc() resource.StateRefreshFunc
+	Target() []string
+	Pending() []string
+	Error() error
+}
+
+// WaitForOperation polls waiter until it reaches one of its target states,
+// a non-retryable error occurs, or timeout elapses. The DNS zone, compute
+// instance and Kubernetes cluster/node pool resources wait through it;
+// resourceDNSRecordSetV2 runs its own resource.StateChangeConf instead.
+func WaitForOperation(ctx context.Context, waiter OperationWaiter, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    waiter.Pending(),
+		Target:     waiter.Target(),
+		Refresh:    waiter.RefreshFunc(),
+		Timeout:    timeout,
+		Delay:      jitter(5 * time.Second),
+		MinTimeout: 3 * time.Second,
+	}
+
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+			return CheckForRetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return waiter.Error()
+}
+
+// CheckForRetryableError classifies an error raised while polling or calling
+// the backend as retryable (HTTP 409/429/502/503/504, or a context deadline
+// that should be retried by an outer caller) or terminal. Besides
+// WaitForOperation, resourceDNSRecordSetV2Create calls it around its own
+// resource.StateChangeConf.
+func CheckForRetryableError(err error) *resource.RetryError {
+	switch e := err.(type) {
+	case gophercloud.ErrDefault409:
+		return resource.RetryableError(e)
+	case gophercloud.ErrDefault429:
+		return resource.RetryableError(e)
+	case gophercloud.ErrDefault503:
+		return resource.RetryableError(e)
+	case gophercloud.ErrUnexpectedResponseCode:
+		switch e.Actual {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return resource.RetryableError(e)
+		}
+		return resource.NonRetryableError(e)
+	}
+
+	if err == context.DeadlineExceeded {
+		return resource.RetryableError(err)
+	}
+
+	return resource.NonRetryableError(err)
+}
+
+// jitter adds up to 20% random variance to d so that many resources waiting
+// on the same backend don't all poll in lockstep.
+func jitter(d time.Duration) time.Duration {
+	spread := float64(d) * 0.2
+	return d + time.Duration(rand.Float64()*spread)
+}
+
+// DNSZoneWaiter waits for an openstack_dns_zone_v2 to reach a terminal
+// status. It is the reference OperationWaiter implementation.
+type DNSZoneWaiter struct {
+	Client  *gophercloud.ServiceClient
+	ZoneID  string
+	target  []string
+	pending []string
+	lastErr error
+}
+
+func NewDNSZoneWaiter(client *gophercloud.ServiceClient, zoneID string, target, pending []string) *DNSZoneWaiter {
+	return &DNSZoneWaiter{Client: client, ZoneID: zoneID, target: target, pending: pending}
+}
+
+func (w *DNSZoneWaiter) RefreshFunc() resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		zone, err := zones.Get(w.Client, w.ZoneID).Extract()
+		if err != nil {
+			if _, ok := err.(gophercloud.ErrDefault404); ok {
+				return zone, "DELETED", nil
+			}
+			return nil, "", err
+		}
+
+		if zone.Status == "ERROR" {
+			w.lastErr = fmt.Errorf("openstack_dns_zone_v2 %s is in ERROR state", w.ZoneID)
+		}
+
+		return zone, zone.Status, nil
+	}
+}
+
+func (w *DNSZoneWaiter) Target() []string  { return w.target }
+func (w *DNSZoneWaiter) Pending() []string { return w.pending }
+func (w *DNSZoneWaiter) Error() error      { return w.lastErr }
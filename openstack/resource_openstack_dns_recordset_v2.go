@@ -0,0 +1,331 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+)
+
+func resourceDNSRecordSetV2() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDNSRecordSetV2Create,
+		ReadContext:   resourceDNSRecordSetV2Read,
+		UpdateContext: resourceDNSRecordSetV2Update,
+		DeleteContext: resourceDNSRecordSetV2Delete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				// Allow import from different project with zone_id/recordset_id:project_id
+				parts := strings.SplitN(d.Id(), ":", 2)
+				if len(parts) == 2 {
+					d.Set("project_id", parts[1])
+				}
+
+				idParts := strings.SplitN(parts[0], "/", 2)
+				if len(idParts) != 2 {
+					return nil, fmt.Errorf("unexpected format of ID (%s), expected <zone_id>/<recordset_id>(:<project_id>)", d.Id())
+				}
+
+				d.Set("zone_id", idParts[0])
+				d.SetId(idParts[1])
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"project_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+
+			"zone_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"A", "AAAA", "CNAME", "MX", "TXT", "SRV", "NS", "PTR", "SSHFP", "SPF",
+				}, false),
+			},
+
+			"ttl": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"records": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					StateFunc: func(v interface{}) string {
+						return strings.TrimSuffix(v.(string), ".")
+					},
+				},
+			},
+
+			"disable_status_check": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+// ResourceDNSRecordSetV2 exposes resourceDNSRecordSetV2 so the viettelidc
+// package can register it in its own ResourcesMap without this package
+// needing to know about viettelidc's resource naming.
+func ResourceDNSRecordSetV2() *schema.Resource {
+	return resourceDNSRecordSetV2()
+}
+
+func resourceDNSRecordSetV2Create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	dnsClient, err := config.DNSV2Client(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating OpenStack DNS client: %s", err)
+	}
+
+	zoneID := d.Get("zone_id").(string)
+	createOpts := recordsets.CreateOpts{
+		Name:        d.Get("name").(string),
+		Type:        d.Get("type").(string),
+		TTL:         d.Get("ttl").(int),
+		Description: d.Get("description").(string),
+		Records:     expandToStringSlice(d.Get("records").([]interface{})),
+	}
+
+	if err := dnsClientSetAuthHeader(d, dnsClient); err != nil {
+		return diag.Errorf("Error setting dns client auth headers: %s", err)
+	}
+
+	log.Printf("[DEBUG] openstack_dns_recordset_v2 create options: %#v", createOpts)
+	n, err := recordsets.Create(dnsClient, zoneID, createOpts).Extract()
+	if err != nil {
+		return diag.Errorf("Error creating openstack_dns_recordset_v2: %s", err)
+	}
+
+	d.SetId(n.ID)
+
+	if d.Get("disable_status_check").(bool) {
+		log.Printf("[DEBUG] Created openstack_dns_recordset_v2 %s: %#v", n.ID, n)
+		return resourceDNSRecordSetV2Read(ctx, d, meta)
+	}
+
+	log.Printf("[DEBUG] Waiting for openstack_dns_recordset_v2 %s to become available", n.ID)
+	stateConf := &resource.StateChangeConf{
+		Target:     []string{"ACTIVE"},
+		Pending:    []string{"PENDING"},
+		Refresh:    dnsRecordSetV2RefreshFunc(dnsClient, zoneID, n.ID),
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Delay:      5 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+
+	err = resource.RetryContext(ctx, stateConf.Timeout, func() *resource.RetryError {
+		_, err = stateConf.WaitForState()
+		if err != nil {
+			log.Printf("[DEBUG] Retrying after error: %s", err)
+			return CheckForRetryableError(err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return diag.Errorf(
+			"Error waiting for openstack_dns_recordset_v2 %s to become active: %s", d.Id(), err)
+	}
+
+	log.Printf("[DEBUG] Created openstack_dns_recordset_v2 %s: %#v", n.ID, n)
+	return resourceDNSRecordSetV2Read(ctx, d, meta)
+}
+
+func resourceDNSRecordSetV2Read(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	dnsClient, err := config.DNSV2Client(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating OpenStack DNS client: %s", err)
+	}
+
+	if err := dnsClientSetAuthHeader(d, dnsClient); err != nil {
+		return diag.Errorf("Error setting dns client auth headers: %s", err)
+	}
+
+	n, err := recordsets.Get(dnsClient, d.Get("zone_id").(string), d.Id()).Extract()
+	if err != nil {
+		return diag.FromErr(CheckDeleted(d, err, "Error retrieving openstack_dns_recordset_v2"))
+	}
+
+	log.Printf("[DEBUG] Retrieved openstack_dns_recordset_v2 %s: %#v", d.Id(), n)
+
+	d.Set("name", n.Name)
+	d.Set("type", n.Type)
+	d.Set("ttl", n.TTL)
+	d.Set("description", n.Description)
+	d.Set("records", n.Records)
+	d.Set("zone_id", n.ZoneID)
+	d.Set("region", GetRegion(d, config))
+	d.Set("project_id", n.ProjectID)
+
+	return nil
+}
+
+func resourceDNSRecordSetV2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	dnsClient, err := config.DNSV2Client(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating OpenStack DNS client: %s", err)
+	}
+
+	var updateOpts recordsets.UpdateOpts
+	changed := false
+
+	if d.HasChange("ttl") {
+		ttl := d.Get("ttl").(int)
+		updateOpts.TTL = &ttl
+		changed = true
+	}
+
+	if d.HasChange("description") {
+		description := d.Get("description").(string)
+		updateOpts.Description = &description
+		changed = true
+	}
+
+	if d.HasChange("records") {
+		updateOpts.Records = expandToStringSlice(d.Get("records").([]interface{}))
+		changed = true
+	}
+
+	if !changed {
+		return resourceDNSRecordSetV2Read(ctx, d, meta)
+	}
+
+	if err := dnsClientSetAuthHeader(d, dnsClient); err != nil {
+		return diag.Errorf("Error setting dns client auth headers: %s", err)
+	}
+
+	zoneID := d.Get("zone_id").(string)
+	log.Printf("[DEBUG] Updating openstack_dns_recordset_v2 %s with options: %#v", d.Id(), updateOpts)
+
+	_, err = recordsets.Update(dnsClient, zoneID, d.Id(), updateOpts).Extract()
+	if err != nil {
+		return diag.Errorf("Error updating openstack_dns_recordset_v2 %s: %s", d.Id(), err)
+	}
+
+	if d.Get("disable_status_check").(bool) {
+		return resourceDNSRecordSetV2Read(ctx, d, meta)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Target:     []string{"ACTIVE"},
+		Pending:    []string{"PENDING"},
+		Refresh:    dnsRecordSetV2RefreshFunc(dnsClient, zoneID, d.Id()),
+		Timeout:    d.Timeout(schema.TimeoutUpdate),
+		Delay:      5 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+
+	_, err = stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return diag.Errorf(
+			"Error waiting for openstack_dns_recordset_v2 %s to become active: %s", d.Id(), err)
+	}
+
+	return resourceDNSRecordSetV2Read(ctx, d, meta)
+}
+
+func resourceDNSRecordSetV2Delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	dnsClient, err := config.DNSV2Client(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating OpenStack DNS client: %s", err)
+	}
+
+	if err := dnsClientSetAuthHeader(d, dnsClient); err != nil {
+		return diag.Errorf("Error setting dns client auth headers: %s", err)
+	}
+
+	zoneID := d.Get("zone_id").(string)
+	err = recordsets.Delete(dnsClient, zoneID, d.Id()).ExtractErr()
+	if err != nil {
+		return diag.FromErr(CheckDeleted(d, err, "Error deleting openstack_dns_recordset_v2"))
+	}
+
+	if d.Get("disable_status_check").(bool) {
+		return nil
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Target:     []string{"DELETED"},
+		Pending:    []string{"ACTIVE", "PENDING"},
+		Refresh:    dnsRecordSetV2RefreshFunc(dnsClient, zoneID, d.Id()),
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		Delay:      5 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+
+	_, err = stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return diag.Errorf(
+			"Error waiting for openstack_dns_recordset_v2 %s to become deleted: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func dnsRecordSetV2RefreshFunc(dnsClient *gophercloud.ServiceClient, zoneID, recordsetID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		recordset, err := recordsets.Get(dnsClient, zoneID, recordsetID).Extract()
+		if err != nil {
+			if _, ok := err.(gophercloud.ErrDefault404); ok {
+				return recordset, "DELETED", nil
+			}
+			return nil, "", err
+		}
+
+		return recordset, recordset.Status, nil
+	}
+}
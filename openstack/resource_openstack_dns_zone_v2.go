@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
@@ -120,6 +119,13 @@ func resourceDNSZoneV2() *schema.Resource {
 	}
 }
 
+// ResourceDNSZoneV2 exposes resourceDNSZoneV2 so the viettelidc package can
+// register it in its own ResourcesMap without this package needing to know
+// about viettelidc's resource naming.
+func ResourceDNSZoneV2() *schema.Resource {
+	return resourceDNSZoneV2()
+}
+
 func resourceDNSZoneV2Create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	config := meta.(*Config)
 	dnsClient, err := config.DNSV2Client(GetRegion(d, config))
@@ -158,25 +164,8 @@ func resourceDNSZoneV2Create(ctx context.Context, d *schema.ResourceData, meta i
 	}
 
 	log.Printf("[DEBUG] Waiting for openstack_dns_zone_v2 %s to become available", n.ID)
-	stateConf := &resource.StateChangeConf{
-		Target:     []string{"ACTIVE"},
-		Pending:    []string{"PENDING"},
-		Refresh:    dnsZoneV2RefreshFunc(dnsClient, n.ID),
-		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      5 * time.Second,
-		MinTimeout: 3 * time.Second,
-	}
-
-	err = resource.RetryContext(ctx, stateConf.Timeout, func() *resource.RetryError {
-		_, err = stateConf.WaitForState()
-		if err != nil {
-			log.Printf("[DEBUG] Retrying after error: %s", err)
-			return checkForRetryableError(err)
-		}
-		return nil
-	})
-
-	if err != nil {
+	waiter := NewDNSZoneWaiter(dnsClient, n.ID, []string{"ACTIVE"}, []string{"PENDING"})
+	if err := WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutCreate)); err != nil {
 		return diag.Errorf(
 			"Error waiting for openstack_dns_zone_v2 %s to become active: %s", d.Id(), err)
 	}
@@ -268,17 +257,8 @@ func resourceDNSZoneV2Update(ctx context.Context, d *schema.ResourceData, meta i
 		return resourceDNSZoneV2Read(ctx, d, meta)
 	}
 
-	stateConf := &resource.StateChangeConf{
-		Target:     []string{"ACTIVE"},
-		Pending:    []string{"PENDING"},
-		Refresh:    dnsZoneV2RefreshFunc(dnsClient, d.Id()),
-		Timeout:    d.Timeout(schema.TimeoutUpdate),
-		Delay:      5 * time.Second,
-		MinTimeout: 3 * time.Second,
-	}
-
-	_, err = stateConf.WaitForStateContext(ctx)
-	if err != nil {
+	waiter := NewDNSZoneWaiter(dnsClient, d.Id(), []string{"ACTIVE"}, []string{"PENDING"})
+	if err := WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutUpdate)); err != nil {
 		return diag.Errorf(
 			"Error waiting for openstack_dns_zone_v2 %s to become active: %s", d.Id(), err)
 	}
@@ -306,17 +286,8 @@ func resourceDNSZoneV2Delete(ctx context.Context, d *schema.ResourceData, meta i
 		return nil
 	}
 
-	stateConf := &resource.StateChangeConf{
-		Target:     []string{"DELETED"},
-		Pending:    []string{"ACTIVE", "PENDING"},
-		Refresh:    dnsZoneV2RefreshFunc(dnsClient, d.Id()),
-		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      5 * time.Second,
-		MinTimeout: 3 * time.Second,
-	}
-
-	_, err = stateConf.WaitForStateContext(ctx)
-	if err != nil {
+	waiter := NewDNSZoneWaiter(dnsClient, d.Id(), []string{"DELETED"}, []string{"ACTIVE", "PENDING"})
+	if err := WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutDelete)); err != nil {
 		return diag.Errorf(
 			"Error waiting for openstack_dns_zone_v2 %s to become deleted: %s", d.Id(), err)
 	}
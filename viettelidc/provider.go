@@ -0,0 +1,173 @@
+package viettelidc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/viettelcloud-provider/terraform-provider-viettelidc/openstack"
+)
+
+// Config is the unified provider configuration. It is a type alias (not a
+// new type) so that resources registered from the openstack package, which
+// type-assert meta.(*openstack.Config), and resources in this package, which
+// type-assert meta.(*Config), operate on exactly the same value.
+type Config = openstack.Config
+
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"auth_url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_AUTH_URL", nil),
+			},
+
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_USERNAME", ""),
+			},
+
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_PASSWORD", ""),
+			},
+
+			"application_credential_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_APPLICATION_CREDENTIAL_ID", ""),
+			},
+
+			"application_credential_secret": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_APPLICATION_CREDENTIAL_SECRET", ""),
+			},
+
+			"project_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_PROJECT_ID", ""),
+			},
+
+			"project_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_PROJECT_NAME", ""),
+			},
+
+			"domain_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_DOMAIN_NAME", "Default"),
+			},
+
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_REGION", ""),
+			},
+
+			"token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_TOKEN", ""),
+			},
+
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_INSECURE", false),
+			},
+
+			"cacert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_CACERT", ""),
+			},
+
+			"client_cert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_CERT", ""),
+			},
+
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VIETTELIDC_KEY", ""),
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"viettelidc_compute_instance":     resourceComputeInstance(),
+			"viettelidc_dns_zone_v2":          openstack.ResourceDNSZoneV2(),
+			"viettelidc_dns_recordset_v2":     openstack.ResourceDNSRecordSetV2(),
+			"viettelidc_kubernetes_cluster":   resourceKubernetesCluster(),
+			"viettelidc_kubernetes_node_pool": resourceKubernetesNodePool(),
+		},
+
+		ConfigureContextFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	config := &Config{
+		AuthURL:                     d.Get("auth_url").(string),
+		Username:                    d.Get("username").(string),
+		Password:                    d.Get("password").(string),
+		ApplicationCredentialID:     d.Get("application_credential_id").(string),
+		ApplicationCredentialSecret: d.Get("application_credential_secret").(string),
+		ProjectID:                   d.Get("project_id").(string),
+		ProjectName:                 d.Get("project_name").(string),
+		DomainName:                  d.Get("domain_name").(string),
+		Region:                      d.Get("region").(string),
+		Token:                       d.Get("token").(string),
+		Insecure:                    d.Get("insecure").(bool),
+		CACertFile:                  d.Get("cacert_file").(string),
+		ClientCertFile:              d.Get("client_cert").(string),
+		ClientKeyFile:               d.Get("client_key").(string),
+	}
+
+	if err := config.LoadAndValidate(); err != nil {
+		return nil, diag.Errorf("Error configuring Viettel IDC provider: %s", err)
+	}
+
+	return config, nil
+}
+
+// GetRegion and CheckDeleted delegate to the openstack package so that
+// resources in this package can keep calling them unqualified, exactly like
+// their openstack-package siblings (resourceDNSZoneV2 et al.) already do.
+func GetRegion(d *schema.ResourceData, config *Config) string {
+	return openstack.GetRegion(d, config)
+}
+
+func CheckDeleted(d *schema.ResourceData, err error, msg string) error {
+	return openstack.CheckDeleted(d, err, msg)
+}
+
+func expandToStringSlice(v []interface{}) []string {
+	s := make([]string, len(v))
+	for i, val := range v {
+		s[i] = val.(string)
+	}
+	return s
+}
+
+func expandToMapStringString(v map[string]interface{}) map[string]string {
+	m := make(map[string]string, len(v))
+	for key, val := range v {
+		if s, ok := val.(string); ok {
+			m[key] = s
+		}
+	}
+	return m
+}
@@ -0,0 +1,541 @@
+package viettelidc
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/secgroups"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/tags"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	"github.com/viettelcloud-provider/terraform-provider-viettelidc/openstack"
+)
+
+func resourceComputeInstance() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceComputeInstanceCreate,
+		ReadContext:   resourceComputeInstanceRead,
+		UpdateContext: resourceComputeInstanceUpdate,
+		DeleteContext: resourceComputeInstanceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"flavor_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"flavor_name"},
+			},
+
+			// flavor_name is only used to resolve flavor_id on create/resize;
+			// servers.Server never reports the flavor's name back (only its
+			// id, via Flavor["id"]), so Read only refreshes flavor_id and
+			// leaves whatever value the user set here alone.
+			"flavor_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"flavor_id"},
+			},
+
+			"image_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"network": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"fixed_ip_v4": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"port": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"security_groups": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"block_device": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"source_type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"destination_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "volume",
+						},
+						"volume_size": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"boot_index": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  0,
+						},
+						"delete_on_termination": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+
+			"key_pair": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"user_data": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				StateFunc: func(v interface{}) string {
+					return userDataHashSum(v.(string))
+				},
+			},
+
+			"metadata": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"access_ip_v4": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"access_ip_v6": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceComputeInstanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	computeClient, err := config.ComputeClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating compute client: %s", err)
+	}
+
+	flavorID := d.Get("flavor_id").(string)
+	if flavorID == "" {
+		id, err := computeFlavorIDFromName(computeClient, d.Get("flavor_name").(string))
+		if err != nil {
+			return diag.Errorf("Error resolving flavor_name for viettelidc_compute_instance: %s", err)
+		}
+		flavorID = id
+	}
+
+	createOpts := &servers.CreateOpts{
+		Name:             d.Get("name").(string),
+		ImageRef:         d.Get("image_id").(string),
+		FlavorRef:        flavorID,
+		SecurityGroups:   expandToStringSlice(d.Get("security_groups").(*schema.Set).List()),
+		AvailabilityZone: d.Get("availability_zone").(string),
+		Networks:         expandComputeInstanceNetworks(d.Get("network").([]interface{})),
+		Metadata:         expandToMapStringString(d.Get("metadata").(map[string]interface{})),
+		UserData:         []byte(d.Get("user_data").(string)),
+	}
+
+	var createOptsBuilder servers.CreateOptsBuilder = createOpts
+	if v, ok := d.GetOk("key_pair"); ok {
+		createOptsBuilder = keypairs.CreateOptsExt{
+			CreateOptsBuilder: createOptsBuilder,
+			KeyName:           v.(string),
+		}
+	}
+
+	blockDevices := expandComputeInstanceBlockDevices(d.Get("block_device").([]interface{}))
+	if len(blockDevices) > 0 {
+		createOptsBuilder = bootfromvolume.CreateOptsExt{
+			CreateOptsBuilder: createOptsBuilder,
+			BlockDevice:       blockDevices,
+		}
+	}
+
+	log.Printf("[DEBUG] viettelidc_compute_instance create options: %#v", createOptsBuilder)
+	server, err := servers.Create(computeClient, createOptsBuilder).Extract()
+	if err != nil {
+		return diag.Errorf("Error creating viettelidc_compute_instance: %s", err)
+	}
+
+	d.SetId(server.ID)
+
+	log.Printf("[DEBUG] Waiting for viettelidc_compute_instance %s to become available", server.ID)
+	waiter := newComputeInstanceWaiter(computeClient, server.ID, []string{"ACTIVE"}, []string{"BUILD"})
+	if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("Error waiting for viettelidc_compute_instance %s to become active: %s", d.Id(), err)
+	}
+
+	if v, ok := d.GetOk("tags"); ok {
+		instanceTags := expandToStringSlice(v.(*schema.Set).List())
+		if _, err := tags.ReplaceAll(computeClient, server.ID, tags.ReplaceAllOpts{Tags: instanceTags}).Extract(); err != nil {
+			return diag.Errorf("Error setting tags on viettelidc_compute_instance %s: %s", server.ID, err)
+		}
+	}
+
+	return resourceComputeInstanceRead(ctx, d, meta)
+}
+
+func resourceComputeInstanceRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	computeClient, err := config.ComputeClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating compute client: %s", err)
+	}
+
+	server, err := servers.Get(computeClient, d.Id()).Extract()
+	if err != nil {
+		return diag.FromErr(CheckDeleted(d, err, "Error retrieving viettelidc_compute_instance"))
+	}
+
+	log.Printf("[DEBUG] Retrieved viettelidc_compute_instance %s: %#v", d.Id(), server)
+
+	d.Set("name", server.Name)
+	d.Set("metadata", server.Metadata)
+	d.Set("status", server.Status)
+	d.Set("region", GetRegion(d, config))
+
+	if flavorID, ok := server.Flavor["id"].(string); ok {
+		d.Set("flavor_id", flavorID)
+	}
+
+	accessIPv4, accessIPv6 := flattenComputeInstanceAccessAddresses(server.Addresses)
+	d.Set("access_ip_v4", accessIPv4)
+	d.Set("access_ip_v6", accessIPv6)
+
+	instanceTags, err := tags.List(computeClient, d.Id()).Extract()
+	if err != nil {
+		log.Printf("[DEBUG] Unable to list tags for viettelidc_compute_instance %s: %s", d.Id(), err)
+	} else {
+		d.Set("tags", instanceTags)
+	}
+
+	return nil
+}
+
+func resourceComputeInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	computeClient, err := config.ComputeClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating compute client: %s", err)
+	}
+
+	if d.HasChange("name") {
+		updateOpts := servers.UpdateOpts{
+			Name: d.Get("name").(string),
+		}
+		if _, err := servers.Update(computeClient, d.Id(), updateOpts).Extract(); err != nil {
+			return diag.Errorf("Error updating name of viettelidc_compute_instance %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("metadata") {
+		metadata := expandToMapStringString(d.Get("metadata").(map[string]interface{}))
+		if _, err := servers.UpdateMetadata(computeClient, d.Id(), servers.MetadataOpts(metadata)).Extract(); err != nil {
+			return diag.Errorf("Error updating metadata of viettelidc_compute_instance %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("security_groups") {
+		old, new := d.GetChange("security_groups")
+		oldSGs := expandToStringSlice(old.(*schema.Set).List())
+		newSGs := expandToStringSlice(new.(*schema.Set).List())
+
+		for _, sg := range oldSGs {
+			if err := secgroups.RemoveServer(computeClient, d.Id(), sg).ExtractErr(); err != nil {
+				log.Printf("[DEBUG] Error removing security group %s from viettelidc_compute_instance %s: %s", sg, d.Id(), err)
+			}
+		}
+		for _, sg := range newSGs {
+			if err := secgroups.AddServer(computeClient, d.Id(), sg).ExtractErr(); err != nil {
+				return diag.Errorf("Error adding security group %s to viettelidc_compute_instance %s: %s", sg, d.Id(), err)
+			}
+		}
+	}
+
+	if d.HasChange("flavor_id") || d.HasChange("flavor_name") {
+		if err := resizeComputeInstance(ctx, d, computeClient); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("tags") {
+		instanceTags := expandToStringSlice(d.Get("tags").(*schema.Set).List())
+		if _, err := tags.ReplaceAll(computeClient, d.Id(), tags.ReplaceAllOpts{Tags: instanceTags}).Extract(); err != nil {
+			return diag.Errorf("Error updating tags on viettelidc_compute_instance %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceComputeInstanceRead(ctx, d, meta)
+}
+
+func resourceComputeInstanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	computeClient, err := config.ComputeClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating compute client: %s", err)
+	}
+
+	if err := servers.Delete(computeClient, d.Id()).ExtractErr(); err != nil {
+		return diag.FromErr(CheckDeleted(d, err, "Error deleting viettelidc_compute_instance"))
+	}
+
+	waiter := newComputeInstanceWaiter(computeClient, d.Id(), []string{"DELETED"}, []string{"ACTIVE", "BUILD", "SHUTOFF"})
+	if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("Error waiting for viettelidc_compute_instance %s to become deleted: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resizeComputeInstance(ctx context.Context, d *schema.ResourceData, computeClient *gophercloud.ServiceClient) error {
+	flavorID := d.Get("flavor_id").(string)
+	flavorName := d.Get("flavor_name").(string)
+	if flavorID == "" || (d.HasChange("flavor_name") && flavorName != "") {
+		id, err := computeFlavorIDFromName(computeClient, flavorName)
+		if err != nil {
+			return fmt.Errorf("error resolving flavor_name for viettelidc_compute_instance %s: %s", d.Id(), err)
+		}
+		flavorID = id
+	}
+
+	resizeOpts := &servers.ResizeOpts{
+		FlavorRef: flavorID,
+	}
+
+	log.Printf("[DEBUG] Resizing viettelidc_compute_instance %s with options: %#v", d.Id(), resizeOpts)
+	if err := servers.Resize(computeClient, d.Id(), resizeOpts).ExtractErr(); err != nil {
+		return fmt.Errorf("error resizing viettelidc_compute_instance %s: %s", d.Id(), err)
+	}
+
+	waiter := newComputeInstanceWaiter(computeClient, d.Id(), []string{"VERIFY_RESIZE"}, []string{"RESIZE"})
+	if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return fmt.Errorf("error waiting for viettelidc_compute_instance %s to resize: %s", d.Id(), err)
+	}
+
+	if err := servers.ConfirmResize(computeClient, d.Id()).ExtractErr(); err != nil {
+		return fmt.Errorf("error confirming resize of viettelidc_compute_instance %s: %s", d.Id(), err)
+	}
+
+	waiter = newComputeInstanceWaiter(computeClient, d.Id(), []string{"ACTIVE"}, []string{"VERIFY_RESIZE"})
+	if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return fmt.Errorf("error waiting for viettelidc_compute_instance %s to confirm resize: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// computeInstanceWaiter waits for a viettelidc_compute_instance to reach a
+// terminal status, satisfying openstack.OperationWaiter so Create, Delete,
+// and resizeComputeInstance can all go through openstack.WaitForOperation
+// instead of hand-rolling their own resource.StateChangeConf.
+type computeInstanceWaiter struct {
+	Client     *gophercloud.ServiceClient
+	InstanceID string
+	target     []string
+	pending    []string
+	lastErr    error
+}
+
+func newComputeInstanceWaiter(client *gophercloud.ServiceClient, instanceID string, target, pending []string) *computeInstanceWaiter {
+	return &computeInstanceWaiter{Client: client, InstanceID: instanceID, target: target, pending: pending}
+}
+
+func (w *computeInstanceWaiter) RefreshFunc() resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		server, err := servers.Get(w.Client, w.InstanceID).Extract()
+		if err != nil {
+			if _, ok := err.(gophercloud.ErrDefault404); ok {
+				return server, "DELETED", nil
+			}
+			return nil, "", err
+		}
+
+		if server.Status == "ERROR" {
+			w.lastErr = fmt.Errorf("viettelidc_compute_instance %s is in error state", w.InstanceID)
+		}
+
+		return server, server.Status, nil
+	}
+}
+
+func (w *computeInstanceWaiter) Target() []string  { return w.target }
+func (w *computeInstanceWaiter) Pending() []string { return w.pending }
+func (w *computeInstanceWaiter) Error() error      { return w.lastErr }
+
+func expandComputeInstanceNetworks(raw []interface{}) []servers.Network {
+	networks := make([]servers.Network, len(raw))
+	for i, v := range raw {
+		network := v.(map[string]interface{})
+		networks[i] = servers.Network{
+			UUID:    network["uuid"].(string),
+			FixedIP: network["fixed_ip_v4"].(string),
+			Port:    network["port"].(string),
+		}
+	}
+	return networks
+}
+
+func expandComputeInstanceBlockDevices(raw []interface{}) []bootfromvolume.BlockDevice {
+	blockDevices := make([]bootfromvolume.BlockDevice, len(raw))
+	for i, v := range raw {
+		bd := v.(map[string]interface{})
+		blockDevices[i] = bootfromvolume.BlockDevice{
+			UUID:                bd["uuid"].(string),
+			SourceType:          bootfromvolume.SourceType(bd["source_type"].(string)),
+			DestinationType:     bootfromvolume.DestinationType(bd["destination_type"].(string)),
+			VolumeSize:          bd["volume_size"].(int),
+			BootIndex:           bd["boot_index"].(int),
+			DeleteOnTermination: bd["delete_on_termination"].(bool),
+		}
+	}
+	return blockDevices
+}
+
+// userDataHashSum stores the hash of user_data instead of the raw value to
+// avoid leaking its (potentially sensitive) contents into the Terraform state diff.
+func userDataHashSum(userData string) string {
+	sum := sha1.Sum([]byte(userData))
+	return hex.EncodeToString(sum[:])
+}
+
+func flattenComputeInstanceAccessAddresses(addresses map[string]interface{}) (string, string) {
+	var accessIPv4, accessIPv6 string
+	for _, raw := range addresses {
+		addrs, ok := raw.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, a := range addrs {
+			addr, ok := a.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			version, _ := addr["version"].(float64)
+			ip, _ := addr["addr"].(string)
+			switch int(version) {
+			case 4:
+				accessIPv4 = ip
+			case 6:
+				accessIPv6 = ip
+			}
+		}
+	}
+	return accessIPv4, accessIPv6
+}
+
+// computeFlavorIDFromName resolves a flavor_name to the flavor ID that
+// servers.CreateOpts.FlavorRef and servers.ResizeOpts.FlavorRef require,
+// since gophercloud's compute flavors API only accepts an ID there.
+func computeFlavorIDFromName(client *gophercloud.ServiceClient, name string) (string, error) {
+	var flavorID string
+
+	pages, err := flavors.ListDetail(client, nil).AllPages()
+	if err != nil {
+		return "", err
+	}
+
+	allFlavors, err := flavors.ExtractFlavors(pages)
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range allFlavors {
+		if f.Name == name {
+			flavorID = f.ID
+			break
+		}
+	}
+
+	if flavorID == "" {
+		return "", fmt.Errorf("no flavor found with name: %s", name)
+	}
+
+	return flavorID, nil
+}
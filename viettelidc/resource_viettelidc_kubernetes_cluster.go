@@ -0,0 +1,441 @@
+package viettelidc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/clusters"
+
+	"github.com/viettelcloud-provider/terraform-provider-viettelidc/openstack"
+)
+
+// kube_config is exposed as a TypeList of a single Resource rather than a
+// TypeMap because the SDK v2 does not support sensitive values inside
+// TypeMap/TypeSet elements (see the provider's OpenStack SDK-v2 migration
+// notes).
+func resourceKubernetesCluster() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKubernetesClusterCreate,
+		ReadContext:   resourceKubernetesClusterRead,
+		UpdateContext: resourceKubernetesClusterUpdate,
+		DeleteContext: resourceKubernetesClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"cluster_template_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"key_pair": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"subnet_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"cluster_cidr": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"service_cidr": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"dns_service_ip": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"api_server_endpoint_access": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "public",
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"public", "private",
+				}, false),
+			},
+
+			"default_node_pool": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"flavor": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"node_count": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  1,
+						},
+						"disk_size": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+
+			"kubeconfig_raw": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"kube_config": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cluster_ca_certificate": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"client_certificate": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"client_key": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"token": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	containerInfraClient, err := config.KubernetesClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating container infra client: %s", err)
+	}
+
+	pool := expandKubernetesDefaultNodePool(d.Get("default_node_pool").([]interface{}))
+	floatingIPEnabled := d.Get("api_server_endpoint_access").(string) == "public"
+
+	createOpts := clusters.CreateOpts{
+		Name:              d.Get("name").(string),
+		ClusterTemplateID: d.Get("cluster_template_id").(string),
+		MasterFlavorID:    pool.flavor,
+		FlavorID:          pool.flavor,
+		NodeCount:         pointerToInt(pool.nodeCount),
+		Keypair:           d.Get("key_pair").(string),
+		FixedNetwork:      d.Get("network_id").(string),
+		FixedSubnet:       d.Get("subnet_id").(string),
+		FloatingIPEnabled: &floatingIPEnabled,
+		Labels: map[string]string{
+			"cluster_cidr":   d.Get("cluster_cidr").(string),
+			"service_cidr":   d.Get("service_cidr").(string),
+			"dns_service_ip": d.Get("dns_service_ip").(string),
+		},
+	}
+
+	log.Printf("[DEBUG] viettelidc_kubernetes_cluster create options: %#v", createOpts)
+	clusterID, err := clusters.Create(containerInfraClient, createOpts).Extract()
+	if err != nil {
+		return diag.Errorf("Error creating viettelidc_kubernetes_cluster: %s", err)
+	}
+
+	d.SetId(clusterID)
+
+	log.Printf("[DEBUG] Waiting for viettelidc_kubernetes_cluster %s to become available", clusterID)
+	waiter := newKubernetesClusterWaiter(containerInfraClient, clusterID, []string{"CREATE_COMPLETE"}, []string{"CREATE_IN_PROGRESS"})
+	if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("Error waiting for viettelidc_kubernetes_cluster %s to become active: %s", d.Id(), err)
+	}
+
+	return resourceKubernetesClusterRead(ctx, d, meta)
+}
+
+func resourceKubernetesClusterRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	containerInfraClient, err := config.KubernetesClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating container infra client: %s", err)
+	}
+
+	cluster, err := clusters.Get(containerInfraClient, d.Id()).Extract()
+	if err != nil {
+		return diag.FromErr(CheckDeleted(d, err, "Error retrieving viettelidc_kubernetes_cluster"))
+	}
+
+	log.Printf("[DEBUG] Retrieved viettelidc_kubernetes_cluster %s: %#v", d.Id(), cluster)
+
+	d.Set("name", cluster.Name)
+	d.Set("status", cluster.Status)
+	d.Set("endpoint", cluster.APIAddress)
+	d.Set("region", GetRegion(d, config))
+	d.Set("cluster_template_id", cluster.ClusterTemplateID)
+	d.Set("version", cluster.COEVersion)
+	d.Set("key_pair", cluster.KeyPair)
+	d.Set("network_id", cluster.FixedNetwork)
+	d.Set("subnet_id", cluster.FixedSubnet)
+
+	apiServerEndpointAccess := "private"
+	if cluster.FloatingIPEnabled {
+		apiServerEndpointAccess = "public"
+	}
+	d.Set("api_server_endpoint_access", apiServerEndpointAccess)
+
+	kubeconfigRaw, err := kubernetesClusterConfig(containerInfraClient, d.Id())
+	if err != nil {
+		log.Printf("[DEBUG] Unable to retrieve kubeconfig for viettelidc_kubernetes_cluster %s: %s", d.Id(), err)
+	} else {
+		d.Set("kubeconfig_raw", kubeconfigRaw)
+	}
+
+	d.Set("kube_config", flattenKubernetesKubeConfig(cluster, kubeconfigRaw))
+
+	return nil
+}
+
+func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	containerInfraClient, err := config.KubernetesClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating container infra client: %s", err)
+	}
+
+	if d.HasChange("default_node_pool") {
+		pool := expandKubernetesDefaultNodePool(d.Get("default_node_pool").([]interface{}))
+		resizeOpts := clusters.ResizeOpts{NodeCount: pointerToInt(pool.nodeCount)}
+
+		log.Printf("[DEBUG] Resizing viettelidc_kubernetes_cluster %s default node pool: %#v", d.Id(), resizeOpts)
+		if _, err := clusters.Resize(containerInfraClient, d.Id(), resizeOpts).Extract(); err != nil {
+			return diag.Errorf("Error resizing viettelidc_kubernetes_cluster %s: %s", d.Id(), err)
+		}
+
+		waiter := newKubernetesClusterWaiter(containerInfraClient, d.Id(), []string{"UPDATE_COMPLETE"}, []string{"UPDATE_IN_PROGRESS"})
+		if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("Error waiting for viettelidc_kubernetes_cluster %s to resize: %s", d.Id(), err)
+		}
+	}
+
+	return resourceKubernetesClusterRead(ctx, d, meta)
+}
+
+func resourceKubernetesClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	containerInfraClient, err := config.KubernetesClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating container infra client: %s", err)
+	}
+
+	if err := clusters.Delete(containerInfraClient, d.Id()).ExtractErr(); err != nil {
+		return diag.FromErr(CheckDeleted(d, err, "Error deleting viettelidc_kubernetes_cluster"))
+	}
+
+	waiter := newKubernetesClusterWaiter(containerInfraClient, d.Id(), []string{"DELETE_COMPLETE"}, []string{"DELETE_IN_PROGRESS", "CREATE_COMPLETE"})
+	if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("Error waiting for viettelidc_kubernetes_cluster %s to become deleted: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// kubernetesClusterWaiter waits for a viettelidc_kubernetes_cluster to reach a
+// terminal status, satisfying openstack.OperationWaiter so Create, Update, and
+// Delete can all go through openstack.WaitForOperation instead of hand-rolling
+// their own resource.StateChangeConf.
+type kubernetesClusterWaiter struct {
+	Client    *gophercloud.ServiceClient
+	ClusterID string
+	target    []string
+	pending   []string
+	lastErr   error
+}
+
+func newKubernetesClusterWaiter(client *gophercloud.ServiceClient, clusterID string, target, pending []string) *kubernetesClusterWaiter {
+	return &kubernetesClusterWaiter{Client: client, ClusterID: clusterID, target: target, pending: pending}
+}
+
+func (w *kubernetesClusterWaiter) RefreshFunc() resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		cluster, err := clusters.Get(w.Client, w.ClusterID).Extract()
+		if err != nil {
+			if _, ok := err.(gophercloud.ErrDefault404); ok {
+				return cluster, "DELETE_COMPLETE", nil
+			}
+			return nil, "", err
+		}
+
+		if cluster.Status == "CREATE_FAILED" || cluster.Status == "UPDATE_FAILED" || cluster.Status == "DELETE_FAILED" {
+			w.lastErr = fmt.Errorf("viettelidc_kubernetes_cluster %s is in %s state", w.ClusterID, cluster.Status)
+		}
+
+		return cluster, cluster.Status, nil
+	}
+}
+
+func (w *kubernetesClusterWaiter) Target() []string  { return w.target }
+func (w *kubernetesClusterWaiter) Pending() []string { return w.pending }
+func (w *kubernetesClusterWaiter) Error() error      { return w.lastErr }
+
+// parseKubeConfig pulls out the fields the kube_config block needs from a
+// raw kubeconfig YAML document without taking on a YAML dependency for a
+// handful of scalar values.
+func parseKubeConfig(raw string) (host, caCert, clientCert, clientKey, token string) {
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "server:"):
+			host = strings.TrimSpace(strings.TrimPrefix(line, "server:"))
+		case strings.HasPrefix(line, "certificate-authority-data:"):
+			caCert = strings.TrimSpace(strings.TrimPrefix(line, "certificate-authority-data:"))
+		case strings.HasPrefix(line, "client-certificate-data:"):
+			clientCert = strings.TrimSpace(strings.TrimPrefix(line, "client-certificate-data:"))
+		case strings.HasPrefix(line, "client-key-data:"):
+			clientKey = strings.TrimSpace(strings.TrimPrefix(line, "client-key-data:"))
+		case strings.HasPrefix(line, "token:"):
+			token = strings.TrimSpace(strings.TrimPrefix(line, "token:"))
+		}
+	}
+	return host, caCert, clientCert, clientKey, token
+}
+
+type kubernetesNodePoolSpec struct {
+	name      string
+	flavor    string
+	nodeCount int
+	diskSize  int
+}
+
+func expandKubernetesDefaultNodePool(raw []interface{}) kubernetesNodePoolSpec {
+	if len(raw) == 0 || raw[0] == nil {
+		return kubernetesNodePoolSpec{}
+	}
+
+	pool := raw[0].(map[string]interface{})
+	return kubernetesNodePoolSpec{
+		name:      pool["name"].(string),
+		flavor:    pool["flavor"].(string),
+		nodeCount: pool["node_count"].(int),
+		diskSize:  pool["disk_size"].(int),
+	}
+}
+
+// kubernetesClusterConfig fetches the kubeconfig generated for the cluster's
+// admin user from the Magnum-compatible "/clusters/{id}/config" action.
+func kubernetesClusterConfig(client *gophercloud.ServiceClient, clusterID string) (string, error) {
+	var result struct {
+		Config string `json:"config"`
+	}
+
+	_, err := client.Get(client.ServiceURL("clusters", clusterID, "config"), &result, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result.Config, nil
+}
+
+func flattenKubernetesKubeConfig(cluster *clusters.Cluster, kubeconfigRaw string) []map[string]interface{} {
+	host, caCert, clientCert, clientKey, token := parseKubeConfig(kubeconfigRaw)
+	if host == "" {
+		host = cluster.APIAddress
+	}
+
+	return []map[string]interface{}{
+		{
+			"host":                   host,
+			"cluster_ca_certificate": caCert,
+			"client_certificate":     clientCert,
+			"client_key":             clientKey,
+			"token":                  token,
+		},
+	}
+}
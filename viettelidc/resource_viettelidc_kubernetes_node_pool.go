@@ -0,0 +1,298 @@
+package viettelidc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/clusters"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/nodegroups"
+
+	"github.com/viettelcloud-provider/terraform-provider-viettelidc/openstack"
+)
+
+func resourceKubernetesNodePool() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKubernetesNodePoolCreate,
+		ReadContext:   resourceKubernetesNodePoolRead,
+		UpdateContext: resourceKubernetesNodePoolUpdate,
+		DeleteContext: resourceKubernetesNodePoolDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"flavor": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"node_count": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"min_count": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+
+			"max_count": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"disk_size": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceKubernetesNodePoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	containerInfraClient, err := config.KubernetesClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating container infra client: %s", err)
+	}
+
+	clusterID := d.Get("cluster_id").(string)
+	minCount := d.Get("min_count").(int)
+	maxCount := d.Get("max_count").(int)
+
+	createOpts := nodegroups.CreateOpts{
+		Name:         d.Get("name").(string),
+		FlavorID:     d.Get("flavor").(string),
+		NodeCount:    pointerToInt(d.Get("node_count").(int)),
+		MinNodeCount: minCount,
+		Labels:       expandToMapStringString(d.Get("labels").(map[string]interface{})),
+	}
+	if maxCount > 0 {
+		createOpts.MaxNodeCount = &maxCount
+	}
+	if diskSize, ok := d.GetOk("disk_size"); ok {
+		createOpts.DockerVolumeSize = pointerToInt(diskSize.(int))
+	}
+
+	log.Printf("[DEBUG] viettelidc_kubernetes_node_pool create options: %#v", createOpts)
+	nodeGroup, err := nodegroups.Create(containerInfraClient, clusterID, createOpts).Extract()
+	if err != nil {
+		return diag.Errorf("Error creating viettelidc_kubernetes_node_pool: %s", err)
+	}
+
+	d.SetId(nodeGroup.UUID)
+
+	waiter := newKubernetesNodePoolWaiter(containerInfraClient, clusterID, nodeGroup.UUID, []string{"CREATE_COMPLETE"}, []string{"CREATE_IN_PROGRESS"})
+	if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("Error waiting for viettelidc_kubernetes_node_pool %s to become active: %s", d.Id(), err)
+	}
+
+	return resourceKubernetesNodePoolRead(ctx, d, meta)
+}
+
+func resourceKubernetesNodePoolRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	containerInfraClient, err := config.KubernetesClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating container infra client: %s", err)
+	}
+
+	nodeGroup, err := nodegroups.Get(containerInfraClient, d.Get("cluster_id").(string), d.Id()).Extract()
+	if err != nil {
+		return diag.FromErr(CheckDeleted(d, err, "Error retrieving viettelidc_kubernetes_node_pool"))
+	}
+
+	log.Printf("[DEBUG] Retrieved viettelidc_kubernetes_node_pool %s: %#v", d.Id(), nodeGroup)
+
+	d.Set("name", nodeGroup.Name)
+	d.Set("flavor", nodeGroup.FlavorID)
+	d.Set("node_count", nodeGroup.NodeCount)
+	d.Set("min_count", nodeGroup.MinNodeCount)
+	d.Set("labels", nodeGroup.Labels)
+	d.Set("status", nodeGroup.Status)
+	d.Set("region", GetRegion(d, config))
+
+	if nodeGroup.MaxNodeCount != nil {
+		d.Set("max_count", *nodeGroup.MaxNodeCount)
+	}
+
+	if nodeGroup.DockerVolumeSize != nil {
+		d.Set("disk_size", *nodeGroup.DockerVolumeSize)
+	}
+
+	return nil
+}
+
+func resourceKubernetesNodePoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	containerInfraClient, err := config.KubernetesClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating container infra client: %s", err)
+	}
+
+	clusterID := d.Get("cluster_id").(string)
+	changed := false
+
+	if d.HasChange("min_count") || d.HasChange("max_count") {
+		var updateOpts []nodegroups.UpdateOptsBuilder
+		if d.HasChange("min_count") {
+			updateOpts = append(updateOpts, nodegroups.UpdateOpts{
+				Op:    nodegroups.ReplaceOp,
+				Path:  "/min_node_count",
+				Value: d.Get("min_count").(int),
+			})
+		}
+		if d.HasChange("max_count") {
+			updateOpts = append(updateOpts, nodegroups.UpdateOpts{
+				Op:    nodegroups.ReplaceOp,
+				Path:  "/max_node_count",
+				Value: d.Get("max_count").(int),
+			})
+		}
+
+		log.Printf("[DEBUG] Updating viettelidc_kubernetes_node_pool %s with options: %#v", d.Id(), updateOpts)
+		if _, err := nodegroups.Update(containerInfraClient, clusterID, d.Id(), updateOpts).Extract(); err != nil {
+			return diag.Errorf("Error updating viettelidc_kubernetes_node_pool %s: %s", d.Id(), err)
+		}
+		changed = true
+	}
+
+	if d.HasChange("node_count") {
+		// node_count isn't a valid nodegroups patch path (gophercloud's
+		// UpdateOpts doc only lists /min_node_count and /max_node_count), so a
+		// manual resize goes through the cluster resize action instead,
+		// targeted at this node group via ResizeOpts.NodeGroup.
+		resizeOpts := clusters.ResizeOpts{
+			NodeCount: pointerToInt(d.Get("node_count").(int)),
+			NodeGroup: d.Get("name").(string),
+		}
+
+		log.Printf("[DEBUG] Resizing viettelidc_kubernetes_node_pool %s: %#v", d.Id(), resizeOpts)
+		if _, err := clusters.Resize(containerInfraClient, clusterID, resizeOpts).Extract(); err != nil {
+			return diag.Errorf("Error resizing viettelidc_kubernetes_node_pool %s: %s", d.Id(), err)
+		}
+		changed = true
+	}
+
+	if changed {
+		waiter := newKubernetesNodePoolWaiter(containerInfraClient, clusterID, d.Id(), []string{"UPDATE_COMPLETE"}, []string{"UPDATE_IN_PROGRESS"})
+		if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("Error waiting for viettelidc_kubernetes_node_pool %s to update: %s", d.Id(), err)
+		}
+	}
+
+	return resourceKubernetesNodePoolRead(ctx, d, meta)
+}
+
+func resourceKubernetesNodePoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	containerInfraClient, err := config.KubernetesClient(GetRegion(d, config))
+	if err != nil {
+		return diag.Errorf("Error creating container infra client: %s", err)
+	}
+
+	clusterID := d.Get("cluster_id").(string)
+	if err := nodegroups.Delete(containerInfraClient, clusterID, d.Id()).ExtractErr(); err != nil {
+		return diag.FromErr(CheckDeleted(d, err, "Error deleting viettelidc_kubernetes_node_pool"))
+	}
+
+	waiter := newKubernetesNodePoolWaiter(containerInfraClient, clusterID, d.Id(), []string{"DELETE_COMPLETE"}, []string{"DELETE_IN_PROGRESS", "CREATE_COMPLETE"})
+	if err := openstack.WaitForOperation(ctx, waiter, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("Error waiting for viettelidc_kubernetes_node_pool %s to become deleted: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// kubernetesNodePoolWaiter waits for a viettelidc_kubernetes_node_pool to
+// reach a terminal status, satisfying openstack.OperationWaiter so Create,
+// Update, and Delete can all go through openstack.WaitForOperation instead of
+// hand-rolling their own resource.StateChangeConf.
+type kubernetesNodePoolWaiter struct {
+	Client      *gophercloud.ServiceClient
+	ClusterID   string
+	NodeGroupID string
+	target      []string
+	pending     []string
+	lastErr     error
+}
+
+func newKubernetesNodePoolWaiter(client *gophercloud.ServiceClient, clusterID, nodeGroupID string, target, pending []string) *kubernetesNodePoolWaiter {
+	return &kubernetesNodePoolWaiter{Client: client, ClusterID: clusterID, NodeGroupID: nodeGroupID, target: target, pending: pending}
+}
+
+func (w *kubernetesNodePoolWaiter) RefreshFunc() resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		nodeGroup, err := nodegroups.Get(w.Client, w.ClusterID, w.NodeGroupID).Extract()
+		if err != nil {
+			if _, ok := err.(gophercloud.ErrDefault404); ok {
+				return nodeGroup, "DELETE_COMPLETE", nil
+			}
+			return nil, "", err
+		}
+
+		if nodeGroup.Status == "CREATE_FAILED" || nodeGroup.Status == "UPDATE_FAILED" || nodeGroup.Status == "DELETE_FAILED" {
+			w.lastErr = fmt.Errorf("viettelidc_kubernetes_node_pool %s is in %s state", w.NodeGroupID, nodeGroup.Status)
+		}
+
+		return nodeGroup, nodeGroup.Status, nil
+	}
+}
+
+func (w *kubernetesNodePoolWaiter) Target() []string  { return w.target }
+func (w *kubernetesNodePoolWaiter) Pending() []string { return w.pending }
+func (w *kubernetesNodePoolWaiter) Error() error      { return w.lastErr }
+
+func pointerToInt(v int) *int {
+	return &v
+}